@@ -0,0 +1,79 @@
+package gopv
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+)
+
+// Accumulates commands to dispatch together. Create one with Client.NewBatch,
+// queue commands with Add, then send them with either Do (a pipelined burst,
+// one request per command) or DoAtomic (mpv's own command_list, which
+// applies as a single indivisible unit). This is a significant win when a
+// script needs to issue many property sets in a row, since all queued
+// commands share one write to the IPC socket.
+type Batch struct {
+	client *Client
+	commands [][]any
+}
+
+// Creates an empty Batch bound to this Client.
+func (c *Client) NewBatch() *Batch {
+	return &Batch{client: c}
+}
+
+// Queues a command onto the batch. Mirrors the arguments of Client.Request.
+func (b *Batch) Add(command ...any) {
+	b.commands = append(b.commands, command)
+}
+
+// Dispatches every queued command as a single pipelined burst: one request id
+// and response channel is registered per command ahead of time, and the whole
+// batch is written to the IPC socket in one go. Each command is still its own
+// independent request to mpv (no atomicity), just coalesced onto one write.
+// The returned slice has one entry per command, in the order they were
+// added; if a command fails, the corresponding entry is nil.
+func (b *Batch) Do(ctx context.Context) ([]any, error) {
+	if len(b.commands) == 0 {
+		return nil, nil
+	}
+
+	requests := make([]*ipcRequest, len(b.commands))
+	for i, command := range b.commands {
+		requests[i] = &ipcRequest{
+			Command: command,
+			RequestID: rand.Int(),
+			Async: true,
+			responseChan: make(chan *ipcResponse, 1),
+		}
+	}
+
+	return b.client.batchSend(ctx, requests)
+}
+
+// Dispatches every queued command as a single mpv command_list request,
+// which mpv processes as one atomic unit rather than as independent
+// requests. Use this instead of Do when the batch must not be observed
+// half-applied. The returned slice has one entry per command, in order.
+func (b *Batch) DoAtomic(ctx context.Context) ([]any, error) {
+	if len(b.commands) == 0 {
+		return nil, nil
+	}
+
+	list := make([]any, len(b.commands))
+	for i, command := range b.commands {
+		list[i] = command
+	}
+
+	data, err := b.client.RequestContext(ctx, "command_list", list)
+	if err != nil {
+		return nil, err
+	}
+
+	results, ok := data.([]any)
+	if !ok {
+		return nil, errors.New("gopv: command_list returned an unexpected response shape")
+	}
+
+	return results, nil
+}
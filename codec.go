@@ -0,0 +1,35 @@
+package gopv
+
+import (
+	"encoding/json"
+
+	"go.pennock.tech/swallowjson"
+)
+
+// Encodes and decodes the wire format used to talk to an mpv IPC server.
+// Encode serializes a single outgoing request, and Decode parses a single
+// incoming line (a request response, property-change, or event) into an
+// ipcResponse. Connect uses NativeCodec; use ConnectWithCodec to plug in
+// an alternative, such as JSONRPC2Codec.
+type Codec interface {
+	Encode(request *ipcRequest) ([]byte, error)
+	Decode(line []byte) (*ipcResponse, error)
+}
+
+// Speaks mpv's native line-delimited JSON IPC protocol. This is the Codec
+// used by Connect.
+type NativeCodec struct{}
+
+func (NativeCodec) Encode(request *ipcRequest) ([]byte, error) {
+	return json.Marshal(request)
+}
+
+func (NativeCodec) Decode(line []byte) (*ipcResponse, error) {
+	response := &ipcResponse{}
+	err := swallowjson.UnmarshalWith(response, "EventData", line)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
@@ -1,12 +1,22 @@
 package gopv
 
-// Generates a suitable path for the mpv IPC server
-func GeneratePath() (string, error) {
-	return generatePath()
-}
+import "net"
 
 // Connects to an active mpv IPC server, and returns a Client struct representing it.
 // onError may be nil, in which case errors are silently ignored.
 func Connect(path string, onError func(error)) (*Client, error) {
 	return connect(path, onError)
 }
+
+// Connects to an active mpv IPC server using a custom wire Codec instead of mpv's
+// native line-delimited JSON (for example JSONRPC2Codec), and returns a Client
+// struct representing it.
+// onError may be nil, in which case errors are silently ignored.
+func ConnectWithCodec(path string, codec Codec, onError func(error)) (*Client, error) {
+	conn, err := dial(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return connectInternal(conn, codec, func() (net.Conn, error) { return dial(path) }, onError, nil), nil
+}
@@ -3,27 +3,65 @@
 package gopv
 
 import (
-	"math/rand"
 	"net"
 	"os"
-	"strconv"
 )
 
-func generatePath() (string, error) {
+func generatePath() (*SocketPath, error) {
 	socketDir := os.TempDir() + "/mpvsockets/"
 	err := os.MkdirAll(socketDir, os.ModePerm)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	for attempt := 0; attempt < maxGenerateAttempts; attempt++ {
+		name, err := randomName()
+		if err != nil {
+			return nil, err
+		}
+
+		path := socketDir + name
+
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL, 0600)
+		if err != nil {
+			if os.IsExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		file.Close()
+		// Claimed uniqueness; release the placeholder so mpv can bind its
+		// own socket at this path.
+		os.Remove(path)
+
+		return &SocketPath{Path: path}, nil
 	}
-	
-	return socketDir + strconv.FormatUint(rand.Uint64(), 10), nil
+
+	return nil, errPathExhausted
+}
+
+func cleanupPath(path string) error {
+	err := os.Remove(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	return err
+}
+
+func dial(path string) (net.Conn, error) {
+	return net.Dial("unix", path)
+}
+
+func listen(path string) (net.Listener, error) {
+	return net.Listen("unix", path)
 }
 
 func connect(path string, onError func(error)) (*Client, error) {
-	conn, err := net.Dial("unix", path)
+	conn, err := dial(path)
 	if err != nil {
 		return nil, err
 	}
 
-	return connectInternal(conn, onError), nil
+	return connectInternal(conn, NativeCodec{}, func() (net.Conn, error) { return dial(path) }, onError, nil), nil
 }
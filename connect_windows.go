@@ -3,21 +3,52 @@
 package gopv
 
 import (
-	"math/rand"
-	"strconv"
+	"net"
 
 	"github.com/Microsoft/go-winio"
 )
 
-func generatePath() (string, error) {
-	return `\\.\pipe\` + strconv.FormatUint(rand.Uint64(), 10), nil
+func generatePath() (*SocketPath, error) {
+	for attempt := 0; attempt < maxGenerateAttempts; attempt++ {
+		name, err := randomName()
+		if err != nil {
+			return nil, err
+		}
+
+		path := `\\.\pipe\` + name
+
+		// CreateNamedPipe fails if the name is already taken, so listening
+		// (then immediately closing) doubles as our exclusivity check.
+		listener, err := winio.ListenPipe(path, nil)
+		if err != nil {
+			continue
+		}
+		listener.Close()
+
+		return &SocketPath{Path: path}, nil
+	}
+
+	return nil, errPathExhausted
+}
+
+func cleanupPath(path string) error {
+	// Named pipes don't leave anything on disk to remove.
+	return nil
+}
+
+func dial(path string) (net.Conn, error) {
+	return winio.DialPipe(path, nil)
+}
+
+func listen(path string) (net.Listener, error) {
+	return winio.ListenPipe(path, nil)
 }
 
 func connect(path string, onError func(error)) (*Client, error) {
-	conn, err := winio.DialPipe(path, nil)
+	conn, err := dial(path)
 	if err != nil {
 		return nil, err
 	}
 
-	return connectInternal(conn, onError), nil
+	return connectInternal(conn, NativeCodec{}, func() (net.Conn, error) { return dial(path) }, onError, nil), nil
 }
@@ -2,14 +2,14 @@ package gopv
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
 	"math/rand"
 	"net"
 	"sync"
-
-	"go.pennock.tech/swallowjson"
+	"time"
 )
 
 type ipcResponse struct {
@@ -28,11 +28,29 @@ type ipcRequest struct {
 	responseChan chan *ipcResponse
 }
 
+type observation struct {
+	property string
+	observer func(any)
+}
+
 // Represents an IPC client. Cannot be copied.
 type Client struct {
-	receiver chan *ipcRequest
+	connMu sync.Mutex
 	conn net.Conn
-	
+	// Redials the underlying connection. Nil for connections that can't be
+	// redialed (for example ones obtained from Server.Accept).
+	dial func() (net.Conn, error)
+
+	receiver chan *ipcRequest
+	batchReceiver chan []*ipcRequest
+	writeMu sync.Mutex
+	codec Codec
+
+	// Non-nil if this Client was obtained from Server.Accept, in which case
+	// incoming lines are treated as commands to dispatch to the server's
+	// registered handlers, rather than responses to our own requests.
+	server *Server
+
 	requestMu sync.Mutex
 	requests map[int]*ipcRequest
 
@@ -40,12 +58,28 @@ type Client struct {
 	listeners map[string]func(map[string]any)
 
 	observerMu sync.Mutex
-	observers map[int]func(any)
-	
+	observers map[int]observation
+
 	onError func(error)
-	// Initially set to true, since this this is for avoiding sending to a closed channel
-	// At the start, the channel is open, but callers may have to wait for the sent value to be actually consumed
-	closed bool
+
+	// If true, an unexpected disconnect (mpv exiting, the socket breaking)
+	// makes the client redial and restore its subscriptions instead of
+	// becoming permanently closed. Only effective if the Client can redial,
+	// i.e. it was created via Connect or ConnectWithCodec.
+	AutoReconnect bool
+	// Called after every successful reconnection (see AutoReconnect). It is not
+	// called for the initial connection, which is already established by the
+	// time Connect returns. Optional.
+	OnConnect func()
+	// Called once the client has stopped being usable (explicit Close,
+	// unrecoverable disconnect, or AutoReconnect giving up), with the error
+	// that caused it (nil for an explicit Close with no prior error, or when
+	// mpv closed the connection cleanly). Optional.
+	OnDisconnect func(error)
+
+	closeOnce sync.Once
+	done chan struct{}
+	closeErr error
 }
 
 // Represents an error sent by mpv.
@@ -58,18 +92,22 @@ func (e MpvError) Error() string {
 
 // Means that the IPC client is closed (so no requests can be sent).
 // Note that the client may be closed without the Close function being called
-// (for example if mpv exits).
+// (for example if mpv exits and AutoReconnect is disabled).
 var ErrClosed = errors.New("IPC client is closed")
 
-func connectInternal(conn net.Conn, onError func(error)) *Client {
+// server is set up front so that readLoop's c.server check is never racing
+// against a later assignment: it must be fully installed before the
+// write/read goroutines, which consult it, ever start.
+func connectInternal(conn net.Conn, codec Codec, dial func() (net.Conn, error), onError func(error), server *Server) *Client {
 	client := &Client{
-		receiver: make(chan *ipcRequest),
 		conn: conn,
-		requestMu: sync.Mutex{},
-		listenerMu: sync.Mutex{},
-		observerMu: sync.Mutex{},
+		dial: dial,
+		receiver: make(chan *ipcRequest),
+		batchReceiver: make(chan []*ipcRequest),
+		codec: codec,
 		onError: onError,
-		closed: false,
+		server: server,
+		done: make(chan struct{}),
 	}
 
 	go client.write()
@@ -84,17 +122,35 @@ func connectInternal(conn net.Conn, onError func(error)) *Client {
 // so the caller is still blocked until mpv returns a response.
 // Use RequestSync, if a synchronous request is desired.
 func (c *Client) Request(command ...any) (any, error) {
-	return c.requestInternal(command, true)
+	return c.RequestContext(context.Background(), command...)
+}
+
+// Same as Request, but the request is aborted with ctx.Err() once ctx is done,
+// instead of blocking forever.
+func (c *Client) RequestContext(ctx context.Context, command ...any) (any, error) {
+	return c.requestInternal(ctx, command, true)
 }
 
 // Queues a synchronous IPC request.
 func (c *Client) RequestSync(command ...any) (any, error) {
-	return c.requestInternal(command, false)
+	return c.RequestSyncContext(context.Background(), command...)
+}
+
+// Same as RequestSync, but the request is aborted with ctx.Err() once ctx is done,
+// instead of blocking forever.
+func (c *Client) RequestSyncContext(ctx context.Context, command ...any) (any, error) {
+	return c.requestInternal(ctx, command, false)
 }
 
 // Queues a request parsed from JSON.
 // A custom request id is added before the request is sent.
 func (c *Client) RequestJSON(requestRaw []byte) (any, error) {
+	return c.RequestJSONContext(context.Background(), requestRaw)
+}
+
+// Same as RequestJSON, but the request is aborted with ctx.Err() once ctx is done,
+// instead of blocking forever.
+func (c *Client) RequestJSONContext(ctx context.Context, requestRaw []byte) (any, error) {
 	request := &ipcRequest{}
 	err := json.Unmarshal(requestRaw, request)
 	if err != nil {
@@ -104,7 +160,7 @@ func (c *Client) RequestJSON(requestRaw []byte) (any, error) {
 	request.RequestID = rand.Int()
 	request.responseChan = make(chan *ipcResponse, 1)
 
-	return c.requestSend(request)
+	return c.requestSend(ctx, request)
 }
 
 // Registers an event listener. If the specified event already has one, it will be overridden.
@@ -112,13 +168,19 @@ func (c *Client) RequestJSON(requestRaw []byte) (any, error) {
 // The map data received by the listener function may be nil.
 // This function already handles enabling the event, so there is no need for another Request call.
 func (c *Client) RegisterListener(event string, listener func(map[string]any)) error {
+	return c.RegisterListenerContext(context.Background(), event, listener)
+}
+
+// Same as RegisterListener, but the underlying enable_event request is aborted with ctx.Err()
+// once ctx is done, instead of blocking forever.
+func (c *Client) RegisterListenerContext(ctx context.Context, event string, listener func(map[string]any)) error {
 	c.listenerMu.Lock()
 	defer c.listenerMu.Unlock()
 
 	if c.listeners == nil {
 		c.listeners = make(map[string]func(map[string]any), 1)
 	} else if _, ok := c.listeners[event]; !ok {
-		_, err := c.Request("enable_event", event)
+		_, err := c.RequestContext(ctx, "enable_event", event)
 		if err != nil {
 			return err
 		}
@@ -143,19 +205,25 @@ func (c *Client) UnregisterListener(event string) {
 // The observer function is always run in a new goroutine.
 // The returned integer is the observation id, which can be passed to UnobserveProperty later.
 func (c *Client) ObserveProperty(property string, observer func(any)) (int, error) {
+	return c.ObservePropertyContext(context.Background(), property, observer)
+}
+
+// Same as ObserveProperty, but the underlying observe_property request is aborted with ctx.Err()
+// once ctx is done, instead of blocking forever.
+func (c *Client) ObservePropertyContext(ctx context.Context, property string, observer func(any)) (int, error) {
 	id := rand.Int()
-	
-	_, err := c.Request("observe_property", id, property)
+
+	_, err := c.RequestContext(ctx, "observe_property", id, property)
 	if err != nil {
 		return 0, err
 	}
 
 	c.observerMu.Lock()
 	if c.observers == nil {
-		c.observers = make(map[int]func(any), 1)
+		c.observers = make(map[int]observation, 1)
 	}
-	
-	c.observers[id] = observer
+
+	c.observers[id] = observation{property: property, observer: observer}
 	c.observerMu.Unlock()
 
 	return id, err
@@ -171,98 +239,364 @@ func (c *Client) UnobserveProperty(id int) {
 	go c.Request("unobserve_property", id)
 }
 
+// Returns a channel that's closed once the client is no longer usable: after
+// an explicit Close, an unrecoverable disconnect, or AutoReconnect giving up.
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Returns the error that caused the client to close, once Done is closed.
+// It is nil if Close was called explicitly with no prior connection error, or
+// if mpv closed the connection cleanly (EOF).
+func (c *Client) Err() error {
+	c.requestMu.Lock()
+	defer c.requestMu.Unlock()
+	return c.closeErr
+}
+
 // Closes the IPC client. Subsequent requests will fail with ErrClosed.
 func (c *Client) Close() {
-	c.closed = true
+	c.close(nil)
+}
+
+// Closes the client for good, recording cause as the reason, waking up every
+// pending request with ErrClosed, and running OnDisconnect. Safe to call more
+// than once, and from any goroutine; only the first call has an effect.
+//
+// This deliberately does not close c.receiver/c.batchReceiver: closing them
+// would race with requestSend/batchSend's select sending on those same
+// channels (a genuine send-on-closed-channel race, not just a logical one).
+// write() instead learns about shutdown by also selecting on c.done.
+func (c *Client) close(cause error) {
+	c.closeOnce.Do(func() {
+		c.requestMu.Lock()
+		c.closeErr = cause
+		for id, request := range c.requests {
+			close(request.responseChan)
+			delete(c.requests, id)
+		}
+		c.requestMu.Unlock()
+
+		close(c.done)
+		c.getConn().Close()
+
+		if c.OnDisconnect != nil {
+			c.OnDisconnect(cause)
+		}
+	})
+}
+
+func (c *Client) isClosed() bool {
+	select {
+	case <-c.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *Client) getConn() net.Conn {
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
+	return c.conn
+}
+
+// Installs conn as the current connection, and returns the one it replaced.
+func (c *Client) setConn(conn net.Conn) net.Conn {
+	c.connMu.Lock()
+	old := c.conn
+	c.conn = conn
+	c.connMu.Unlock()
 
-	close(c.receiver)
-	c.conn.Close()
+	return old
 }
 
 func (c *Client) write() {
 	for {
-		req, ok := <-c.receiver
-		if !ok {
+		select {
+		case req := <-c.receiver:
+			c.writeRequests([]*ipcRequest{req})
+		case batch := <-c.batchReceiver:
+			c.writeRequests(batch)
+		case <-c.done:
 			return
 		}
-		
-		body, err := json.Marshal(req)
+	}
+}
+
+// Registers and writes one or more requests, coalescing them into a single
+// conn.Write call. This is what lets Batch dispatch many requests with only
+// one syscall.
+func (c *Client) writeRequests(requests []*ipcRequest) {
+	var body []byte
+
+	c.requestMu.Lock()
+	if c.requests == nil {
+		c.requests = make(map[int]*ipcRequest, len(requests))
+	}
+
+	for _, req := range requests {
+		encoded, err := c.codec.Encode(req)
 		if err != nil {
+			c.requestMu.Unlock()
 			c.publishError(err)
-			continue
+			return
 		}
 
-		c.requestMu.Lock()
-		if c.requests == nil {
-			c.requests = make(map[int]*ipcRequest, 1)
-		}
-		
 		c.requests[req.RequestID] = req
-		c.requestMu.Unlock()
+		body = append(body, encoded...)
+		body = append(body, '\n')
+	}
+	c.requestMu.Unlock()
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
 
-		// Realistically, this can never fail because the pipe has been closed,
-		// since the read loop should immediately exit, and close the request channel.
-		_, err = c.conn.Write(body)
+	_, err := c.getConn().Write(body)
+	if err != nil {
+		c.publishError(err)
+	}
+}
+
+// Writes a single already-encoded line to the connection, for server-side
+// responses that don't go through the request/write queue.
+func (c *Client) writeLine(body []byte) {
+	body = append(body, '\n')
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	_, err := c.getConn().Write(body)
+	if err != nil {
+		c.publishError(err)
+	}
+}
+
+func (c *Client) read() {
+	for {
+		reconnected := c.readLoop(bufio.NewReader(c.getConn()))
+		if !reconnected {
+			return
+		}
+	}
+}
+
+// Reads lines off reader, dispatching each, until the connection breaks.
+// Returns true if the client reconnected and reading should resume with a
+// fresh reader, false if the client is now closed for good.
+func (c *Client) readLoop(reader *bufio.Reader) bool {
+	for {
+		data, err := reader.ReadBytes('\n')
 		if err != nil {
-			c.publishError(err)
+			return c.handleDisconnect(err)
+		}
+
+		if c.server != nil {
+			c.server.handle(c, data)
 			continue
 		}
-		_, err = c.conn.Write([]byte{'\n'})
+
+		response, err := c.codec.Decode(data)
 		if err != nil {
 			c.publishError(err)
 			continue
 		}
+
+		c.dispatch(response)
 	}
 }
 
-func (c *Client) read() {
-	reader := bufio.NewReader(c.conn)
-	for {
-		data, err := reader.ReadBytes('\n')
-		if err != nil {
-			if errors.Is(err, io.EOF) {
-				c.Close()
-				return
-			} else {
-				c.publishError(err)
-				continue
-			}
-		}
+func (c *Client) handleDisconnect(err error) bool {
+	if errors.Is(err, io.EOF) {
+		err = nil
+	} else {
+		c.publishError(err)
+	}
+
+	if c.AutoReconnect && c.dial != nil && c.reconnect() {
+		return true
+	}
+
+	c.close(err)
+	return false
+}
 
-		response := &ipcResponse{}
-		err = swallowjson.UnmarshalWith(response, "EventData", data)
+// Attempts to redial with a bounded backoff, and if successful, restores the
+// client's subscriptions and replays any still-pending requests.
+func (c *Client) reconnect() bool {
+	const maxAttempts = 5
+	delay := 200 * time.Millisecond
+
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		conn, err := c.dial()
 		if err != nil {
 			c.publishError(err)
+			time.Sleep(delay)
+			delay *= 2
 			continue
 		}
 
-		c.dispatch(response)
+		if c.isClosed() {
+			// Close raced with the dial; don't resurrect a client the
+			// caller already shut down.
+			conn.Close()
+			return false
+		}
+
+		if old := c.setConn(conn); old != nil {
+			old.Close()
+		}
+
+		c.resubscribe()
+
+		if c.isClosed() {
+			return false
+		}
+
+		if c.OnConnect != nil {
+			c.OnConnect()
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// Re-issues enable_event and observe_property for every still-registered
+// listener and observer, and rewrites any requests that were already sent
+// but never got a response before the disconnect.
+func (c *Client) resubscribe() {
+	c.listenerMu.Lock()
+	events := make([]string, 0, len(c.listeners))
+	for event := range c.listeners {
+		events = append(events, event)
+	}
+	c.listenerMu.Unlock()
+
+	for _, event := range events {
+		go c.Request("enable_event", event)
+	}
+
+	c.observerMu.Lock()
+	observers := make(map[int]observation, len(c.observers))
+	for id, obs := range c.observers {
+		observers[id] = obs
+	}
+	c.observerMu.Unlock()
+
+	for id, obs := range observers {
+		go c.Request("observe_property", id, obs.property)
+	}
+
+	c.requestMu.Lock()
+	pending := make([]*ipcRequest, 0, len(c.requests))
+	for _, request := range c.requests {
+		pending = append(pending, request)
+	}
+	c.requestMu.Unlock()
+
+	if len(pending) > 0 && !c.isClosed() {
+		c.writeRequests(pending)
 	}
 }
 
-func (c *Client) requestSend(request *ipcRequest) (any, error) {
-	if c.closed {
+func (c *Client) requestSend(ctx context.Context, request *ipcRequest) (any, error) {
+	if c.isClosed() {
 		return nil, ErrClosed
 	}
 
-	c.receiver <- request
-	response := <-request.responseChan
-	if response.Error != "success" {
-		return nil, &MpvError{response.Error}
+	select {
+	case c.receiver <- request:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-c.done:
+		return nil, ErrClosed
 	}
 
-	return response.Data, nil	
+	select {
+	case response, ok := <-request.responseChan:
+		if !ok {
+			return nil, ErrClosed
+		}
+		if response.Error != "success" {
+			return nil, &MpvError{response.Error}
+		}
+
+		return response.Data, nil
+	case <-ctx.Done():
+		// The write goroutine may have already registered the request (and the read
+		// goroutine may be about to dispatch a response for it); either way, drop our reference so
+		// a late response isn't delivered to a channel nobody is reading anymore.
+		c.requestMu.Lock()
+		delete(c.requests, request.RequestID)
+		c.requestMu.Unlock()
+
+		return nil, ctx.Err()
+	case <-c.done:
+		return nil, ErrClosed
+	}
 }
 
-func (c *Client) requestInternal(command []any, async bool) (any, error) {
+// Sends several requests as a single coalesced write, and collects their
+// responses in order. If any request fails, the first resulting MpvError is
+// returned alongside the results of all the other requests (the entries of
+// failed requests are nil).
+func (c *Client) batchSend(ctx context.Context, requests []*ipcRequest) ([]any, error) {
+	if c.isClosed() {
+		return nil, ErrClosed
+	}
+
+	select {
+	case c.batchReceiver <- requests:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-c.done:
+		return nil, ErrClosed
+	}
+
+	results := make([]any, len(requests))
+	var firstErr error
+
+	for i, request := range requests {
+		select {
+		case response, ok := <-request.responseChan:
+			if !ok {
+				return results, ErrClosed
+			}
+			if response.Error != "success" {
+				if firstErr == nil {
+					firstErr = &MpvError{response.Error}
+				}
+				continue
+			}
+
+			results[i] = response.Data
+		case <-ctx.Done():
+			c.requestMu.Lock()
+			for _, pending := range requests[i:] {
+				delete(c.requests, pending.RequestID)
+			}
+			c.requestMu.Unlock()
+
+			return results, ctx.Err()
+		case <-c.done:
+			return results, ErrClosed
+		}
+	}
+
+	return results, firstErr
+}
+
+func (c *Client) requestInternal(ctx context.Context, command []any, async bool) (any, error) {
 	request := &ipcRequest{
 		Command: command,
 		RequestID: rand.Int(),
 		Async: async,
-		responseChan: make(chan *ipcResponse),
+		responseChan: make(chan *ipcResponse, 1),
 	}
 
-	return c.requestSend(request)
+	return c.requestSend(ctx, request)
 }
 
 func (c *Client) dispatch(response *ipcResponse) {
@@ -270,11 +604,11 @@ func (c *Client) dispatch(response *ipcResponse) {
 	case "":
 		c.requestMu.Lock()
 		defer c.requestMu.Unlock()
-	
+
 		if c.requests == nil {
 			return
 		}
-		
+
 		request, ok := c.requests[response.RequestID]
 		if !ok {
 			return
@@ -286,14 +620,14 @@ func (c *Client) dispatch(response *ipcResponse) {
 	case "property-change":
 		c.observerMu.Lock()
 		defer c.observerMu.Unlock()
-		
+
 		if c.observers == nil {
 			return
 		}
 
-		observer, ok := c.observers[response.ID]
+		obs, ok := c.observers[response.ID]
 		if ok {
-			go observer(response.Data)
+			go obs.observer(response.Data)
 		}
 	default:
 		c.listenerMu.Lock()
@@ -0,0 +1,113 @@
+package gopv
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func startTestServer(t *testing.T) (*Server, string) {
+	t.Helper()
+
+	socketPath, err := GeneratePath()
+	if err != nil {
+		t.Fatalf("GeneratePath: %v", err)
+	}
+	t.Cleanup(func() { socketPath.Cleanup() })
+
+	server, err := Listen(socketPath.Path)
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	t.Cleanup(func() { server.Close() })
+
+	return server, socketPath.Path
+}
+
+func TestClientServerRequestResponse(t *testing.T) {
+	server, path := startTestServer(t)
+
+	server.HandleCommand("get_property", func(args []any) (any, error) {
+		if len(args) != 1 || args[0] != "pause" {
+			return nil, errors.New("unexpected args")
+		}
+
+		return true, nil
+	})
+
+	accepted := make(chan struct{})
+	go func() {
+		defer close(accepted)
+		server.Accept()
+	}()
+
+	client, err := Connect(path, nil)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer client.Close()
+
+	select {
+	case <-accepted:
+	case <-time.After(time.Second):
+		t.Fatal("server never accepted the connection")
+	}
+
+	value, err := GetProperty[bool](client, "pause")
+	if err != nil {
+		t.Fatalf("GetProperty: %v", err)
+	}
+	if !value {
+		t.Fatalf("got pause = %v, want true", value)
+	}
+}
+
+func TestRequestContextCancellation(t *testing.T) {
+	server, path := startTestServer(t)
+
+	go server.Accept()
+
+	client, err := Connect(path, nil)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = client.RequestContext(ctx, "get_property", "pause")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got err = %v, want context.Canceled", err)
+	}
+}
+
+func TestCloseWakesPendingRequests(t *testing.T) {
+	// Deliberately never Accept the connection, so no response can ever
+	// arrive: the pending Request must be woken up by Close instead.
+	_, path := startTestServer(t)
+
+	client, err := Connect(path, nil)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.Request("get_property", "pause")
+		done <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	client.Close()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrClosed) {
+			t.Fatalf("got err = %v, want ErrClosed", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("request did not wake up after Close")
+	}
+}
@@ -0,0 +1,98 @@
+package gopv
+
+import "encoding/json"
+
+type jsonrpc2Request struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  []any  `json:"params"`
+	ID      int    `json:"id"`
+}
+
+type jsonrpc2Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type jsonrpc2Message struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	ID     *int            `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *jsonrpc2Error  `json:"error"`
+}
+
+type jsonrpc2PropertyChange struct {
+	ID   int `json:"id"`
+	Data any `json:"data"`
+}
+
+// Speaks JSON-RPC 2.0 (https://www.jsonrpc.org/specification) instead of mpv's
+// native IPC protocol, for interop with JSON-RPC tooling such as
+// golang.org/x/tools/internal/jsonrpc2. Commands are sent as the "command"
+// method, and property-change/event notifications arrive as notifications
+// (no id) named after the event.
+type JSONRPC2Codec struct{}
+
+func (JSONRPC2Codec) Encode(request *ipcRequest) ([]byte, error) {
+	return json.Marshal(jsonrpc2Request{
+		JSONRPC: "2.0",
+		Method:  "command",
+		Params:  request.Command,
+		ID:      request.RequestID,
+	})
+}
+
+func (JSONRPC2Codec) Decode(line []byte) (*ipcResponse, error) {
+	message := &jsonrpc2Message{}
+	err := json.Unmarshal(line, message)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ipcResponse{}
+
+	if message.ID != nil {
+		response.RequestID = *message.ID
+
+		if message.Error != nil {
+			response.Error = message.Error.Message
+			return response, nil
+		}
+
+		response.Error = "success"
+		if len(message.Result) > 0 {
+			err = json.Unmarshal(message.Result, &response.Data)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return response, nil
+	}
+
+	switch message.Method {
+	case "property-change":
+		change := jsonrpc2PropertyChange{}
+		if len(message.Params) > 0 {
+			err = json.Unmarshal(message.Params, &change)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		response.Event = "property-change"
+		response.ID = change.ID
+		response.Data = change.Data
+	default:
+		response.Event = message.Method
+		if len(message.Params) > 0 {
+			err = json.Unmarshal(message.Params, &response.EventData)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return response, nil
+}
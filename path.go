@@ -1,18 +1,42 @@
 package gopv
 
 import (
-	"fmt"
-	"math/rand"
-	"os"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 )
 
-// Generates a suitable path for an mpv socket.
-func GeneratePath() (string, error) {
-	ipcDir := os.TempDir() + "/mpvsockets"
-	err := os.MkdirAll(ipcDir, os.ModePerm)
+const maxGenerateAttempts = 10
+
+var errPathExhausted = errors.New("gopv: could not find an unclaimed socket path")
+
+// Represents a freshly generated, collision-checked socket path for an mpv
+// IPC server. Call Cleanup once it's no longer needed (after mpv has exited)
+// to remove anything left behind at the path.
+type SocketPath struct {
+	Path string
+}
+
+// Removes whatever was left behind at the path. Safe to call even if nothing
+// was ever created there.
+func (s *SocketPath) Cleanup() error {
+	return cleanupPath(s.Path)
+}
+
+// Generates a suitable path for an mpv IPC server. Unlike picking a random
+// name outright, the candidate is claimed via an exclusive create before
+// being handed back, so two processes started in quick succession aren't
+// handed the same path.
+func GeneratePath() (*SocketPath, error) {
+	return generatePath()
+}
+
+func randomName() (string, error) {
+	var buf [16]byte
+	_, err := rand.Read(buf[:])
 	if err != nil {
 		return "", err
 	}
-	
-	return fmt.Sprintf("%s/%d", ipcDir, rand.Uint32()), nil
+
+	return hex.EncodeToString(buf[:]), nil
 }
@@ -0,0 +1,151 @@
+package gopv
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Gets a property and decodes it into T. This is a typed wrapper around
+// Client.Request("get_property", name).
+func GetProperty[T any](c *Client, name string) (T, error) {
+	return GetPropertyContext[T](context.Background(), c, name)
+}
+
+// Same as GetProperty, but the underlying request is aborted with ctx.Err()
+// once ctx is done, instead of blocking forever.
+func GetPropertyContext[T any](ctx context.Context, c *Client, name string) (T, error) {
+	data, err := c.RequestContext(ctx, "get_property", name)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return decodeProperty[T](data)
+}
+
+// Sets a property to v. This is a typed wrapper around
+// Client.Request("set_property", name, v).
+func SetProperty[T any](c *Client, name string, v T) error {
+	return SetPropertyContext(context.Background(), c, name, v)
+}
+
+// Same as SetProperty, but the underlying request is aborted with ctx.Err()
+// once ctx is done, instead of blocking forever.
+func SetPropertyContext[T any](ctx context.Context, c *Client, name string, v T) error {
+	_, err := c.RequestContext(ctx, "set_property", name, v)
+	return err
+}
+
+// Starts observing a property, decoding every update into T before calling fn.
+// Updates that fail to decode into T are silently dropped.
+// The returned integer is the observation id, which can be passed to UnobserveProperty.
+func ObservePropertyTyped[T any](c *Client, name string, fn func(T)) (int, error) {
+	return ObservePropertyTypedContext(context.Background(), c, name, fn)
+}
+
+// Same as ObservePropertyTyped, but the underlying observe_property request is
+// aborted with ctx.Err() once ctx is done, instead of blocking forever.
+func ObservePropertyTypedContext[T any](ctx context.Context, c *Client, name string, fn func(T)) (int, error) {
+	return c.ObservePropertyContext(ctx, name, func(data any) {
+		value, err := decodeProperty[T](data)
+		if err == nil {
+			fn(value)
+		}
+	})
+}
+
+// Decodes an any returned by mpv (typically float64, string, bool, map[string]any,
+// or nil for an unavailable property) into T, round-tripping through JSON.
+// float64 is special-cased for integer T, since mpv's JSON node type has no
+// distinct integer representation.
+func decodeProperty[T any](data any) (T, error) {
+	var value T
+
+	if data == nil {
+		return value, nil
+	}
+
+	switch ptr := any(&value).(type) {
+	case *int:
+		if f, ok := data.(float64); ok {
+			*ptr = int(f)
+			return value, nil
+		}
+	case *int64:
+		if f, ok := data.(float64); ok {
+			*ptr = int64(f)
+			return value, nil
+		}
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return value, err
+	}
+
+	err = json.Unmarshal(encoded, &value)
+	return value, err
+}
+
+// Provides compile-time checked access to a handful of common mpv properties,
+// instead of the stringly-typed GetProperty/SetProperty calls. Create one
+// with Client.Properties.
+//
+// This is a small, hand-maintained subset of mpv's property list, not a
+// generated binding covering all of it — there is no generator here. Add to
+// it the same way as the existing methods, by wrapping GetProperty/SetProperty
+// for the property in question; anything not listed here is still reachable
+// through GetProperty/SetProperty/ObservePropertyTyped directly.
+type Properties struct {
+	client *Client
+}
+
+// Returns a Properties wrapper around this Client.
+func (c *Client) Properties() Properties {
+	return Properties{client: c}
+}
+
+// time-pos: current playback position, in seconds.
+func (p Properties) TimePos() (float64, error) {
+	return GetProperty[float64](p.client, "time-pos")
+}
+
+// Same as TimePos, but respects ctx.
+func (p Properties) TimePosContext(ctx context.Context) (float64, error) {
+	return GetPropertyContext[float64](ctx, p.client, "time-pos")
+}
+
+// duration: length of the current file, in seconds.
+func (p Properties) Duration() (float64, error) {
+	return GetProperty[float64](p.client, "duration")
+}
+
+// pause: whether playback is paused.
+func (p Properties) Pause() (bool, error) {
+	return GetProperty[bool](p.client, "pause")
+}
+
+// Sets the pause property.
+func (p Properties) SetPause(v bool) error {
+	return SetProperty(p.client, "pause", v)
+}
+
+// volume: current volume, 0-100 (can go higher if volume-max allows it).
+func (p Properties) Volume() (float64, error) {
+	return GetProperty[float64](p.client, "volume")
+}
+
+// Sets the volume property.
+func (p Properties) SetVolume(v float64) error {
+	return SetProperty(p.client, "volume", v)
+}
+
+// filename: filename of the current file, without the path.
+func (p Properties) Filename() (string, error) {
+	return GetProperty[string](p.client, "filename")
+}
+
+// path: full path/URL of the current file.
+func (p Properties) Path() (string, error) {
+	return GetProperty[string](p.client, "path")
+}
@@ -0,0 +1,106 @@
+package gopv
+
+import (
+	"encoding/json"
+	"net"
+	"sync"
+)
+
+// Listens for incoming mpv IPC connections, mirroring the role mpv itself
+// normally plays. Create one with Listen, then call Accept in a loop. This
+// is useful for building an in-process fake mpv for tests of applications
+// that use gopv, or a proxy/multiplexer daemon that fans one real mpv
+// instance out to several consumers.
+type Server struct {
+	listener net.Listener
+
+	handlerMu sync.Mutex
+	handlers map[string]func(args []any) (any, error)
+}
+
+// Starts listening on path (a Unix socket path, or a named pipe path on
+// Windows) for incoming mpv IPC connections.
+func Listen(path string) (*Server, error) {
+	listener, err := listen(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Server{listener: listener}, nil
+}
+
+// Registers a handler for an incoming command. If the specified command
+// already has one, it will be overridden. The handler receives the command's
+// arguments, i.e. everything after the command name itself.
+func (s *Server) HandleCommand(command string, handler func(args []any) (any, error)) {
+	s.handlerMu.Lock()
+	defer s.handlerMu.Unlock()
+
+	if s.handlers == nil {
+		s.handlers = make(map[string]func(args []any) (any, error), 1)
+	}
+
+	s.handlers[command] = handler
+}
+
+// Accepts the next incoming connection, and returns a Client struct
+// representing it. Commands sent by the peer are dispatched to the handlers
+// registered via HandleCommand; the peer's own requests/observers/listeners
+// machinery on the returned Client is unused, since that Client represents
+// our (the server's) end of the connection.
+func (s *Server) Accept() (*Client, error) {
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	client := connectInternal(conn, NativeCodec{}, nil, nil, s)
+
+	return client, nil
+}
+
+// Stops accepting new connections. Connections already accepted are unaffected.
+func (s *Server) Close() error {
+	return s.listener.Close()
+}
+
+func (s *Server) handle(client *Client, line []byte) {
+	request := &ipcRequest{}
+	err := json.Unmarshal(line, request)
+	if err != nil {
+		client.publishError(err)
+		return
+	}
+
+	go s.respond(client, request)
+}
+
+func (s *Server) respond(client *Client, request *ipcRequest) {
+	response := &ipcResponse{RequestID: request.RequestID, Error: "success"}
+
+	if len(request.Command) == 0 {
+		response.Error = "invalid command"
+	} else {
+		name, _ := request.Command[0].(string)
+
+		s.handlerMu.Lock()
+		handler, ok := s.handlers[name]
+		s.handlerMu.Unlock()
+
+		if !ok {
+			response.Error = "unsupported command"
+		} else if data, err := handler(request.Command[1:]); err != nil {
+			response.Error = err.Error()
+		} else {
+			response.Data = data
+		}
+	}
+
+	body, err := json.Marshal(response)
+	if err != nil {
+		client.publishError(err)
+		return
+	}
+
+	client.writeLine(body)
+}
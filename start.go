@@ -0,0 +1,73 @@
+package gopv
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"time"
+)
+
+// Spawns mpv with a freshly generated --input-ipc-server socket (plus --idle,
+// and any extra args), waits for the socket to become dialable, and connects
+// to it. If ctx is cancelled, or mpv exits, before its IPC socket appears, the
+// wait is aborted with ctx.Err() or an error explaining that mpv exited.
+// The caller owns the returned *exec.Cmd (Wait is already called on it in a
+// background goroutine, so Wait must not be called on it again) for signaling the
+// process; the returned SocketPath's Cleanup should be called once mpv exits.
+func StartMPV(ctx context.Context, args ...string) (*Client, *SocketPath, *exec.Cmd, error) {
+	socketPath, err := GeneratePath()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	mpvArgs := append([]string{"--input-ipc-server=" + socketPath.Path, "--idle"}, args...)
+
+	cmd := exec.Command("mpv", mpvArgs...)
+	err = cmd.Start()
+	if err != nil {
+		socketPath.Cleanup()
+		return nil, nil, nil, err
+	}
+
+	exited := make(chan struct{})
+	go func() {
+		cmd.Wait()
+		close(exited)
+	}()
+
+	client, err := waitForSocket(ctx, socketPath.Path, exited)
+	if err != nil {
+		socketPath.Cleanup()
+		return nil, nil, cmd, err
+	}
+
+	return client, socketPath, cmd, nil
+}
+
+// Retries Connect with a bounded exponential backoff until it succeeds, ctx
+// is done, or exited is closed (mpv exited before ever listening), since mpv
+// takes a moment to start listening on its IPC socket.
+func waitForSocket(ctx context.Context, path string, exited <-chan struct{}) (*Client, error) {
+	const maxDelay = time.Second
+	delay := 50 * time.Millisecond
+
+	for {
+		client, err := Connect(path, nil)
+		if err == nil {
+			return client, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-exited:
+			return nil, errors.New("gopv: mpv exited before its IPC socket appeared")
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+		if delay > maxDelay {
+			delay = maxDelay
+		}
+	}
+}